@@ -1,6 +1,9 @@
 package simplepool
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // Config configures a new pool.
 type Config[T any] struct {
@@ -14,6 +17,70 @@ type Config[T any] struct {
 	// needed.
 	// This function is optional.
 	DestroyFunc func(T)
+	// ResetFunc is a function that resets an object's state before it's
+	// returned to the pool's idle objects by Put.
+	// If ResetFunc returns an error, Put destroys the object and replaces
+	// it with a freshly constructed one, so the pool's capacity doesn't
+	// shrink.
+	// This function is optional.
+	ResetFunc func(T) error
+	// ValidateFunc is a function that checks whether an idle object is
+	// still usable before it's handed out by Get.
+	// If ValidateFunc returns false, Get destroys the object and replaces
+	// it with a freshly constructed one, so the pool's capacity doesn't
+	// shrink.
+	// This function is optional.
+	ValidateFunc func(T) bool
+
+	// PrefillParallelism is the number of objects New builds concurrently.
+	// Zero means New builds objects one at a time, as it always has.
+	// Must be >= 0.
+	PrefillParallelism int
+	// PrefillTimeout bounds how long New waits for prefill to finish when
+	// PrefillParallelism is greater than zero. Zero means no timeout.
+	PrefillTimeout time.Duration
+
+	// EventHook holds optional callbacks for observing the pool's
+	// lifecycle. See EventHook for details.
+	// This field is optional.
+	EventHook *EventHook
+
+	// MaxLifetime is how long an object may live before Put destroys it
+	// and replaces it with a freshly constructed one, so the pool's
+	// capacity doesn't shrink. Zero means objects never expire by age.
+	// Must be >= 0.
+	MaxLifetime time.Duration
+	// MaxUses is how many times Get may hand out an object before Put
+	// destroys it and replaces it with a freshly constructed one, so the
+	// pool's capacity doesn't shrink. Zero means objects are never
+	// retired by use count.
+	// Must be >= 0.
+	MaxUses int
+}
+
+// EventHook holds optional callbacks for observing a pool's lifecycle.
+// A nil callback is simply not called.
+type EventHook struct {
+	// OnGet is called each time Get successfully returns an object.
+	OnGet func()
+	// OnPut is called each time Put is called.
+	OnPut func()
+	// OnWait is called after Get has waited for an idle object to become
+	// available, with how long it waited. It isn't called when an idle
+	// object was immediately available.
+	OnWait func(waited time.Duration)
+	// OnNewError is called whenever NewFunc returns an error, whether
+	// during prefill or while replacing an object destroyed by ResetFunc,
+	// ValidateFunc, MaxLifetime, or MaxUses.
+	OnNewError func(err error)
+	// OnDestroy is called each time the pool destroys an object, even if
+	// DestroyFunc is nil.
+	OnDestroy func()
+	// OnStopStart is called once, when the pool transitions to stopping.
+	OnStopStart func()
+	// OnStopDone is called once, when every object the pool owns has been
+	// destroyed.
+	OnStopDone func()
 }
 
 // Check checks the configuration.
@@ -26,5 +93,14 @@ func (c *Config[T]) Check() error {
 	if c.NewFunc == nil {
 		return errors.New("newFunc is required")
 	}
+	if c.PrefillParallelism < 0 {
+		return errors.New("prefillParallelism must be greater than or equal to zero")
+	}
+	if c.MaxLifetime < 0 {
+		return errors.New("maxLifetime must be greater than or equal to zero")
+	}
+	if c.MaxUses < 0 {
+		return errors.New("maxUses must be greater than or equal to zero")
+	}
 	return nil
 }
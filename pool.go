@@ -9,6 +9,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
@@ -18,12 +21,110 @@ var (
 
 // Pool is a generic object pool that manages a collection of objects of type T.
 type Pool[T any] struct {
-	newFunc     func() (T, error) // required
-	destroyFunc func(T)           // optional
+	newFunc      func() (T, error) // required
+	destroyFunc  func(T)           // optional
+	resetFunc    func(T) error     // optional
+	validateFunc func(T) bool      // optional
+	eventHook    *EventHook        // optional
 
-	idle chan T // cap = count
+	idle chan Lease[T] // cap = count
 
 	stopping chan struct{}
+	stopped  chan struct{} // closed once every object has been destroyed
+
+	waitingGetters atomic.Int64
+	totalGets      atomic.Int64
+	totalPuts      atomic.Int64
+	totalWaitNanos atomic.Int64
+	totalNewErrors atomic.Int64
+
+	maxLifetime time.Duration
+	maxUses     int
+
+	// lost holds one token for each object that was retired by Put but
+	// couldn't be replaced immediately because NewFunc failed. refill
+	// redeems a token by trying again, one per call, so the pool
+	// eventually climbs back to Count. drain redeems any tokens still
+	// outstanding by simply not waiting for those objects, so a run of
+	// NewFunc failures can't leave it waiting forever for objects that
+	// no longer exist.
+	lost chan struct{}
+}
+
+// tracking holds the per-object bookkeeping used to enforce MaxLifetime and
+// MaxUses.
+type tracking struct {
+	createdAt time.Time
+	uses      int
+}
+
+// Lease is an object checked out from the pool by Get, TryGet, or
+// GetWithTimeout. Pass it back to Put to return Object to the pool.
+//
+// A Lease carries Object's tracking bookkeeping (creation time and use
+// count) with it for the whole time it's checked out, so Put always checks
+// the object it's actually getting back against MaxLifetime and MaxUses,
+// never some other object's bookkeeping.
+type Lease[T any] struct {
+	// Object is the checked-out object.
+	Object T
+
+	tracking
+}
+
+// newLease wraps object as a freshly created Lease.
+func newLease[T any](object T) Lease[T] {
+	return Lease[T]{Object: object, tracking: tracking{createdAt: time.Now()}}
+}
+
+// Stats is a snapshot of a pool's state and lifetime counters, returned by
+// Pool.Stats.
+type Stats struct {
+	// Capacity is the pool's configured Count.
+	Capacity int
+	// Idle is the number of objects currently idle.
+	Idle int
+	// Busy is the number of objects currently handed out by Get. Once the
+	// pool is stopping, objects that Stop has already destroyed are counted
+	// here too.
+	Busy int
+	// Lost is the number of objects retired by Put that couldn't be
+	// replaced yet because NewFunc failed. refill retries them on a later
+	// Put, and drain accounts for them directly if the pool is stopped
+	// first. Idle + Busy + Lost always sums to Capacity.
+	Lost int
+	// WaitingGetters is the number of Get calls currently waiting for an
+	// idle object.
+	WaitingGetters int64
+	// TotalGets is the number of times Get has successfully returned an
+	// object.
+	TotalGets int64
+	// TotalPuts is the number of times Put has been called.
+	TotalPuts int64
+	// TotalWaitNanos is the total time, in nanoseconds, that Get calls
+	// have spent waiting for an idle object.
+	TotalWaitNanos int64
+	// TotalNewErrors is the number of times NewFunc has returned an
+	// error, whether during prefill or while replacing an object.
+	TotalNewErrors int64
+}
+
+// Stats returns a snapshot of the pool's current state and lifetime
+// counters.
+func (p *Pool[T]) Stats() Stats {
+	idle := len(p.idle)
+	lost := len(p.lost)
+	return Stats{
+		Capacity:       cap(p.idle),
+		Idle:           idle,
+		Busy:           cap(p.idle) - idle - lost,
+		Lost:           lost,
+		WaitingGetters: p.waitingGetters.Load(),
+		TotalGets:      p.totalGets.Load(),
+		TotalPuts:      p.totalPuts.Load(),
+		TotalWaitNanos: p.totalWaitNanos.Load(),
+		TotalNewErrors: p.totalNewErrors.Load(),
+	}
 }
 
 // New creates a new object pool.
@@ -31,9 +132,14 @@ type Pool[T any] struct {
 // New checks the provided config by calling config.Check(). If there's an
 // error, New returns it.
 //
-// Otherwise, New immediately creates the pool objects. If there's an error
-// creating one of those objects, New destroys the objects it created and
-// returns the error.
+// Otherwise, New immediately creates the pool objects. If PrefillParallelism
+// is zero, New creates them one at a time. Otherwise, New fans the work out
+// across up to PrefillParallelism goroutines, optionally bounded by
+// PrefillTimeout.
+//
+// If there's an error creating one of the objects, or if PrefillTimeout
+// elapses first, New destroys the objects it created and returns the error,
+// wrapped in ErrNew.
 func New[T any](config Config[T]) (*Pool[T], error) {
 	err := config.Check()
 	if err != nil {
@@ -41,80 +147,419 @@ func New[T any](config Config[T]) (*Pool[T], error) {
 	}
 
 	p := &Pool[T]{
-		newFunc:     config.NewFunc,
-		destroyFunc: config.DestroyFunc,
-		idle:        make(chan T, config.Count),
-		stopping:    make(chan struct{}),
+		newFunc:      config.NewFunc,
+		destroyFunc:  config.DestroyFunc,
+		resetFunc:    config.ResetFunc,
+		validateFunc: config.ValidateFunc,
+		eventHook:    config.EventHook,
+		idle:         make(chan Lease[T], config.Count),
+		stopping:     make(chan struct{}),
+		stopped:      make(chan struct{}),
+		maxLifetime:  config.MaxLifetime,
+		maxUses:      config.MaxUses,
+		lost:         make(chan struct{}, config.Count),
+	}
+
+	if config.PrefillParallelism == 0 {
+		if err := p.prefillSequential(); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	if err := p.prefillParallel(config.PrefillParallelism, config.PrefillTimeout); err != nil {
+		return nil, err
 	}
 
+	return p, nil
+}
+
+// prefillSequential creates the pool's objects one at a time.
+func (p *Pool[T]) prefillSequential() error {
 	for range cap(p.idle) {
-		object, err := p.newFunc()
+		object, err := p.newObject()
 		if err != nil {
-			for range len(p.idle) {
-				object := <-p.idle
-				if p.destroyFunc != nil {
-					p.destroyFunc(object)
+			p.destroyBuffered()
+			return fmt.Errorf("%w: %v", ErrNew, err)
+		}
+		p.idle <- newLease(object)
+	}
+	return nil
+}
+
+// prefillParallel creates the pool's objects using up to parallelism
+// goroutines at once, aborting the remaining work as soon as one of them
+// fails or timeout elapses.
+//
+// prefillParallel always waits for every goroutine it started to finish
+// before returning, so no construction is ever left in flight, and it
+// destroys any object built by a goroutine that lost the race against
+// cancellation.
+func (p *Pool[T]) prefillParallel(parallelism int, timeout time.Duration) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if timeout > 0 {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, timeout)
+		defer timeoutCancel()
+	}
+
+	n := cap(p.idle)
+	sem := make(chan struct{}, parallelism)
+	built := make(chan Lease[T], n)
+	errs := make(chan error, 1)
+
+	var wg sync.WaitGroup
+loop:
+	for range n {
+		select {
+		case <-ctx.Done():
+			break loop
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			object, err := p.newObject()
+			if err != nil {
+				select {
+				case errs <- err:
+				default:
 				}
+				cancel()
+				return
 			}
-			return nil, fmt.Errorf("%w: %v", ErrNew, err)
+			lease := newLease(object)
+
+			select {
+			case built <- lease:
+			case <-ctx.Done():
+				p.destroyObject(lease.Object)
+			}
+		}()
+	}
+	wg.Wait()
+	close(built)
+
+	for lease := range built {
+		p.idle <- lease
+	}
+
+	select {
+	case err := <-errs:
+		p.destroyBuffered()
+		return fmt.Errorf("%w: %v", ErrNew, err)
+	default:
+	}
+
+	if ctx.Err() != nil {
+		p.destroyBuffered()
+		return fmt.Errorf("%w: %v", ErrNew, ctx.Err())
+	}
+
+	return nil
+}
+
+// destroyBuffered destroys every object currently buffered in the idle
+// channel, without waiting for any more to arrive. It's used during New,
+// before the pool is handed to callers, so there's nothing else competing
+// for the channel.
+func (p *Pool[T]) destroyBuffered() {
+	for range len(p.idle) {
+		lease := <-p.idle
+		p.destroyObject(lease.Object)
+	}
+}
+
+// newObject constructs a new object via NewFunc, reporting any error via
+// TotalNewErrors and OnNewError.
+func (p *Pool[T]) newObject() (T, error) {
+	object, err := p.newFunc()
+	if err != nil {
+		p.totalNewErrors.Add(1)
+		if p.eventHook != nil && p.eventHook.OnNewError != nil {
+			p.eventHook.OnNewError(err)
 		}
-		p.idle <- object
 	}
+	return object, err
+}
 
-	return p, nil
+// destroyObject destroys object via DestroyFunc, if set, and reports the
+// destruction via OnDestroy.
+func (p *Pool[T]) destroyObject(object T) {
+	if p.destroyFunc != nil {
+		p.destroyFunc(object)
+	}
+	if p.eventHook != nil && p.eventHook.OnDestroy != nil {
+		p.eventHook.OnDestroy()
+	}
+}
+
+// expired reports whether lease has reached MaxLifetime or MaxUses.
+func (p *Pool[T]) expired(lease Lease[T]) bool {
+	if p.maxUses > 0 && lease.uses >= p.maxUses {
+		return true
+	}
+	if p.maxLifetime > 0 && time.Since(lease.createdAt) >= p.maxLifetime {
+		return true
+	}
+	return false
 }
 
 // Stop stops the pool.
 //
-// If the pool is already stopping, or has already stopped, Stop does nothing.
-//
-// Otherwise, Stop destroys all idle objects and then waits for all busy
-// objects to be destroyed before returning.
+// Stop is a wrapper around StopContext that passes context.Background(), so
+// it always waits for every busy object to be returned and destroyed.
 func (p *Pool[T]) Stop() {
+	_ = p.StopContext(context.Background())
+}
+
+// StopContext stops the pool, bounded by ctx.
+//
+// If the pool isn't already stopping or stopped, StopContext marks it as
+// stopping, so that Get starts returning ErrStoppingOrStopped, and starts
+// destroying idle objects as they're returned by Put.
+//
+// StopContext then waits for every object to be destroyed, or for ctx to be
+// done, whichever comes first. If ctx is done first, StopContext returns
+// ctx.Err(), but the pool remains in the stopping state and the destruction
+// of objects returned by later Put calls continues in the background until
+// every object has been accounted for.
+//
+// If the pool is already stopping, or has already stopped, StopContext just
+// waits on ctx and the pool's existing drain.
+func (p *Pool[T]) StopContext(ctx context.Context) error {
 	select {
 	case <-p.stopping:
-		return
 	default:
 		close(p.stopping)
+		if p.eventHook != nil && p.eventHook.OnStopStart != nil {
+			p.eventHook.OnStopStart()
+		}
+		go p.drain()
 	}
 
-	for range cap(p.idle) {
-		object := <-p.idle
-		if p.destroyFunc != nil {
-			p.destroyFunc(object)
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drain destroys every object the pool owns, waiting for busy objects to be
+// returned by Put as necessary, and then marks the pool as fully stopped.
+//
+// An object that Put retired but couldn't replace (see lost) is never coming
+// back, so drain accepts its lost token in place of waiting on it, rather
+// than blocking forever for an object that no longer exists.
+func (p *Pool[T]) drain() {
+	for remaining := cap(p.idle); remaining > 0; remaining-- {
+		select {
+		case lease := <-p.idle:
+			p.destroyObject(lease.Object)
+		case <-p.lost:
 		}
 	}
+	if p.eventHook != nil && p.eventHook.OnStopDone != nil {
+		p.eventHook.OnStopDone()
+	}
+	close(p.stopped)
 }
 
-// Get returns an object from the pool, and an error.
+// Get returns a Lease on an object from the pool, and an error.
 //
-// (If the error is not nil the object will be the zero value of the type T.)
+// (If the error is not nil the Lease will be the zero value.)
 //
 // If the pool is stopping or stopped, Get returns an error.
 //
 // Otherwise, if there are idle objects, Get returns the least recently used
-// idle object (FIFO).
+// idle object (FIFO). If no idle object is immediately available, Get waits
+// for one to be returned to the pool by Put, and reports how long it waited
+// via OnWait.
 //
-// Otherwise, Get waits for an object to be returned to the pool by Put.
+// If ValidateFunc is set and returns false for that object, Get destroys it
+// and returns a freshly constructed replacement instead. If construction of
+// the replacement fails, Get returns the error, wrapped in ErrNew.
 //
 // (Waiting Get calls are served in FIFO order.)
 //
 // Get stops waiting when the provided context is cancelled or when Stop is
 // called.
-func (p *Pool[T]) Get(ctx context.Context) (T, error) {
+func (p *Pool[T]) Get(ctx context.Context) (Lease[T], error) {
+	select {
+	case lease := <-p.idle:
+		return p.afterGet(lease)
+	default:
+	}
+
+	p.waitingGetters.Add(1)
+	started := time.Now()
+
 	select {
-	case object := <-p.idle:
-		return object, nil
+	case lease := <-p.idle:
+		p.afterWait(started)
+		return p.afterGet(lease)
 	case <-ctx.Done():
-		var zero T
-		return zero, ctx.Err()
+		p.afterWait(started)
+		return Lease[T]{}, ctx.Err()
+	case <-p.stopping:
+		p.afterWait(started)
+		return Lease[T]{}, ErrStoppingOrStopped
+	}
+}
+
+// afterWait records that a waiting Get call has finished waiting, whether it
+// succeeded or not.
+func (p *Pool[T]) afterWait(started time.Time) {
+	p.waitingGetters.Add(-1)
+	waited := time.Since(started)
+	p.totalWaitNanos.Add(int64(waited))
+	if p.eventHook != nil && p.eventHook.OnWait != nil {
+		p.eventHook.OnWait(waited)
+	}
+}
+
+// afterGet validates lease's object (if ValidateFunc is set), replacing it
+// if necessary, records the use, and reports the completed Get via TotalGets
+// and OnGet.
+func (p *Pool[T]) afterGet(lease Lease[T]) (Lease[T], error) {
+	if p.validateFunc != nil && !p.validateFunc(lease.Object) {
+		replacement, err := p.replace(lease)
+		if err != nil {
+			return Lease[T]{}, err
+		}
+		lease = replacement
+	}
+	lease.uses++
+
+	p.totalGets.Add(1)
+	if p.eventHook != nil && p.eventHook.OnGet != nil {
+		p.eventHook.OnGet()
+	}
+
+	return lease, nil
+}
+
+// TryGet returns a Lease on an object from the pool without waiting.
+//
+// If an idle object is available, TryGet returns it, along with true and a
+// nil error. If the pool is stopping or stopped, TryGet returns an error.
+// Otherwise, TryGet returns false and a nil error; it's up to the caller to
+// decide what to do, e.g. retry, wait by calling Get, or shed the request.
+//
+// Like Get, if ValidateFunc is set and returns false for the returned
+// object, TryGet destroys it and returns a freshly constructed replacement
+// instead.
+func (p *Pool[T]) TryGet() (Lease[T], bool, error) {
+	select {
+	case <-p.stopping:
+		return Lease[T]{}, false, ErrStoppingOrStopped
+	default:
+	}
+
+	select {
+	case lease := <-p.idle:
+		lease, err := p.afterGet(lease)
+		if err != nil {
+			return Lease[T]{}, false, err
+		}
+		return lease, true, nil
 	case <-p.stopping:
-		var zero T
-		return zero, ErrStoppingOrStopped
+		return Lease[T]{}, false, ErrStoppingOrStopped
+	default:
+		return Lease[T]{}, false, nil
+	}
+}
+
+// GetWithTimeout is a convenience wrapper around Get that returns a Lease on
+// an object from the pool, waiting no longer than d.
+func (p *Pool[T]) GetWithTimeout(d time.Duration) (Lease[T], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return p.Get(ctx)
+}
+
+// Put returns a Lease's object to the pool.
+//
+// If ResetFunc is set and returns an error for that object, Put destroys the
+// object and, if it can construct a replacement, puts the replacement back
+// instead.
+//
+// Otherwise, if the object has reached MaxLifetime or MaxUses, Put destroys
+// it and replaces it the same way.
+//
+// Either way, if construction of the replacement fails, the failure is
+// reported via OnNewError, and Put doesn't swallow the shortfall: replace
+// records it as a lost slot, which a later call to Put retries via refill, so
+// the pool still ends up back at its configured Count.
+func (p *Pool[T]) Put(lease Lease[T]) {
+	p.totalPuts.Add(1)
+	if p.eventHook != nil && p.eventHook.OnPut != nil {
+		p.eventHook.OnPut()
+	}
+
+	if p.resetFunc != nil {
+		if err := p.resetFunc(lease.Object); err != nil {
+			replacement, err := p.replace(lease)
+			if err != nil {
+				return
+			}
+			lease = replacement
+		}
+	}
+
+	if p.expired(lease) {
+		replacement, err := p.replace(lease)
+		if err != nil {
+			return
+		}
+		lease = replacement
 	}
+
+	p.idle <- lease
+	p.refill()
+}
+
+// replace destroys lease's object and constructs its replacement, so that a
+// failed ResetFunc, ValidateFunc, or expiry check doesn't shrink the pool
+// below its configured Count.
+//
+// If construction of the replacement fails, replace records the slot as lost,
+// so refill (or, during shutdown, drain) accounts for it later, and returns
+// the error, wrapped in ErrNew.
+func (p *Pool[T]) replace(lease Lease[T]) (Lease[T], error) {
+	p.destroyObject(lease.Object)
+
+	replacement, err := p.newObject()
+	if err != nil {
+		p.lost <- struct{}{}
+		return Lease[T]{}, fmt.Errorf("%w: %v", ErrNew, err)
+	}
+
+	return newLease(replacement), nil
 }
 
-// Put returns an object to the pool.
-func (p *Pool[T]) Put(object T) {
-	p.idle <- object
+// refill tries to repair a pool that's one object short because an earlier
+// Put couldn't build a replacement for a retired object. It makes at most one
+// attempt per call, redeeming one lost token if it succeeds; if NewFunc fails
+// again, the token goes back and the next Put tries again.
+func (p *Pool[T]) refill() {
+	select {
+	case <-p.lost:
+	default:
+		return
+	}
+
+	replacement, err := p.newObject()
+	if err != nil {
+		p.lost <- struct{}{}
+		return
+	}
+
+	p.idle <- newLease(replacement)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -254,7 +255,7 @@ func TestPoolStopWithBusyObjects(t *testing.T) {
 	}
 
 	// Get some objects and keep track of them
-	busyObjs := make([]int, 0, 5)
+	busyObjs := make([]simplepool.Lease[int], 0, 5)
 	for range 5 {
 		obj, err := p.Get(context.Background())
 		if err != nil {
@@ -298,6 +299,415 @@ func TestPoolStopWithBusyObjects(t *testing.T) {
 	}
 }
 
+// This test verifies that MaxLifetime and MaxUses work for a non-comparable
+// T, such as a struct holding a slice. The pool's internal bookkeeping must
+// never use the object itself as a map key, or this would panic.
+func TestPoolMaxUsesNonComparableObject(t *testing.T) {
+	t.Parallel()
+
+	type buffer struct {
+		data []byte
+	}
+
+	var destroyed atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[buffer]{
+			Count:       1,
+			NewFunc:     func() (buffer, error) { return buffer{data: make([]byte, 0, 16)}, nil },
+			DestroyFunc: func(buffer) { destroyed.Add(1) },
+			MaxUses:     2,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	for range 2 {
+		obj, err := p.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to get object: %v", err)
+		}
+		p.Put(obj)
+	}
+
+	if got := destroyed.Load(); got != 1 {
+		t.Fatalf("Expected object to be destroyed once after reaching MaxUses, got: %d", got)
+	}
+}
+
+// This test verifies that Put destroys and replaces an object once
+// MaxLifetime has elapsed, and leaves younger objects alone.
+func TestPoolMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	var destroyed atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count:       1,
+			NewFunc:     func() (int, error) { return 0, nil },
+			DestroyFunc: func(int) { destroyed.Add(1) },
+			MaxLifetime: 50 * time.Millisecond,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	obj, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+	p.Put(obj)
+
+	if got := destroyed.Load(); got != 0 {
+		t.Fatalf("Expected object not yet destroyed, got: %d", got)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	obj, err = p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+	p.Put(obj)
+
+	if got := destroyed.Load(); got != 1 {
+		t.Fatalf("Expected object to be destroyed once MaxLifetime elapsed, got: %d", got)
+	}
+}
+
+// This test is a regression test for a bug in an earlier version of the
+// pool, where each object's MaxUses/MaxLifetime bookkeeping was tracked by
+// return order (in a side channel) rather than by the object's own Lease,
+// and so could be misattributed between objects. It checks out every
+// object, then cycles just one of them through many Put/Get round-trips
+// while the others sit busy, and verifies that exactly the expected number
+// of destructions happen -- not more, not fewer -- which would only be true
+// if each Lease's use count is tracked against that Lease alone.
+func TestPoolMaxUsesTracksLeaseNotReturnOrder(t *testing.T) {
+	t.Parallel()
+
+	const (
+		count      = 3
+		maxUses    = 2
+		roundTrips = 20
+	)
+
+	var destroyed atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count:       count,
+			NewFunc:     func() (int, error) { return 0, nil },
+			DestroyFunc: func(int) { destroyed.Add(1) },
+			MaxUses:     maxUses,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	// Check out every object, so their Leases are all in flight at once.
+	leases := make([]simplepool.Lease[int], 0, count)
+	for range count {
+		lease, err := p.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to get object: %v", err)
+		}
+		leases = append(leases, lease)
+	}
+
+	// Cycle just one of the checked-out Leases through many round-trips,
+	// while the other two stay busy the whole time.
+	hot := leases[0]
+	for range roundTrips {
+		p.Put(hot)
+		hot, err = p.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to get object: %v", err)
+		}
+	}
+	p.Put(hot)
+	p.Put(leases[1])
+	p.Put(leases[2])
+
+	// Each Get hands the hot object one use closer to MaxUses, so it's
+	// destroyed every maxUses round-trips; the other two objects are never
+	// used again, so they aren't destroyed at all.
+	want := int64(roundTrips / maxUses)
+	if got := destroyed.Load(); got != want {
+		t.Fatalf("Expected %d destructions from a per-Lease use count, got: %d", want, got)
+	}
+}
+
+// This test verifies that ResetFunc is called on every Put, and that an
+// object that resets successfully is reused rather than replaced.
+func TestPoolResetFunc(t *testing.T) {
+	t.Parallel()
+
+	var resets, built, destroyed atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 1,
+			NewFunc: func() (int, error) {
+				return int(built.Add(1)), nil
+			},
+			DestroyFunc: func(int) { destroyed.Add(1) },
+			ResetFunc: func(int) error {
+				resets.Add(1)
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	for range 3 {
+		obj, err := p.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to get object: %v", err)
+		}
+		p.Put(obj)
+	}
+
+	if got := resets.Load(); got != 3 {
+		t.Errorf("Expected ResetFunc to be called 3 times, got: %d", got)
+	}
+	if got := built.Load(); got != 1 {
+		t.Errorf("Expected no replacement object to be built, got: %d", got)
+	}
+	if got := destroyed.Load(); got != 0 {
+		t.Errorf("Expected no object to be destroyed, got: %d", got)
+	}
+}
+
+// This test verifies that ValidateFunc is called on every Get, and that an
+// object that validates successfully is handed out as-is rather than
+// replaced.
+func TestPoolValidateFunc(t *testing.T) {
+	t.Parallel()
+
+	var validations, built, destroyed atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 1,
+			NewFunc: func() (int, error) {
+				return int(built.Add(1)), nil
+			},
+			DestroyFunc: func(int) { destroyed.Add(1) },
+			ValidateFunc: func(int) bool {
+				validations.Add(1)
+				return true
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	for range 3 {
+		obj, err := p.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to get object: %v", err)
+		}
+		p.Put(obj)
+	}
+
+	if got := validations.Load(); got != 3 {
+		t.Errorf("Expected ValidateFunc to be called 3 times, got: %d", got)
+	}
+	if got := built.Load(); got != 1 {
+		t.Errorf("Expected no replacement object to be built, got: %d", got)
+	}
+	if got := destroyed.Load(); got != 0 {
+		t.Errorf("Expected no object to be destroyed, got: %d", got)
+	}
+}
+
+// This test verifies that a ResetFunc failure doesn't cost the pool a slot
+// when its replacement also fails to construct. It creates a scenario where:
+//
+//  1. The pool has two objects (Count=2)
+//  2. ResetFunc always returns an error, so both objects are retired on Put
+//  3. NewFunc fails only on the third call, i.e. the first replacement
+//     attempt
+//
+// Without routing the ResetFunc-failure path through replace's lost-slot
+// accounting, the pool would only ever be able to account for one of its two
+// objects, and Stop would block forever.
+func TestPoolResetFuncFailedReplace(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 2,
+			NewFunc: func() (int, error) {
+				if calls.Add(1) == 3 {
+					return 0, fmt.Errorf("no more objects")
+				}
+				return 0, nil
+			},
+			ResetFunc: func(int) error {
+				return fmt.Errorf("reset always fails")
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+
+	obj1, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+	obj2, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	p.Put(obj1) // replacement attempt fails (the third NewFunc call) and is lost
+	p.Put(obj2) // replacement succeeds, and refill recovers the lost slot
+
+	done := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return; a failed ResetFunc replacement lost a slot permanently")
+	}
+}
+
+// This test verifies that Stop doesn't hang forever when an object retired
+// for exceeding MaxUses can't be replaced because NewFunc fails. It creates a
+// scenario where:
+//
+//  1. The pool has two objects (Count=2)
+//  2. MaxUses is 1, so both objects are retired the first time they're put back
+//  3. NewFunc fails on every replacement attempt after prefill
+//
+// Without lost-token accounting, the pool would permanently lose both slots
+// and Stop would block forever waiting for objects that no longer exist.
+func TestPoolStopAfterFailedReplace(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 2,
+			NewFunc: func() (int, error) {
+				if calls.Add(1) <= 2 {
+					return 0, nil
+				}
+				return 0, fmt.Errorf("no more objects")
+			},
+			MaxUses: 1,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+
+	obj1, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+	obj2, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	// Both objects reached MaxUses, and NewFunc fails for both of their
+	// replacements, so both slots end up as outstanding lost tokens.
+	p.Put(obj1)
+	p.Put(obj2)
+
+	done := make(chan struct{})
+	go func() {
+		p.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return; a lost slot left drain waiting forever")
+	}
+}
+
+// This test verifies StopContext's core contract: if ctx fires before every
+// busy object has been returned, StopContext returns ctx.Err() rather than
+// blocking, but the pool stays in the stopping state, and the background
+// drain it started keeps running and eventually destroys every object once
+// they're all returned.
+func TestPoolStopContextTimeout(t *testing.T) {
+	t.Parallel()
+
+	var destroyed atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count:       2,
+			NewFunc:     func() (int, error) { return 0, nil },
+			DestroyFunc: func(int) { destroyed.Add(1) },
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+
+	obj1, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+	obj2, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = p.StopContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	// The pool must still be stopping: Get should fail immediately rather
+	// than hand out either busy object once it's returned.
+	if _, err := p.Get(context.Background()); !errors.Is(err, simplepool.ErrStoppingOrStopped) {
+		t.Fatalf("Expected ErrStoppingOrStopped, got: %v", err)
+	}
+
+	// The background drain should still be running, so returning the busy
+	// objects now should still get them destroyed.
+	p.Put(obj1)
+	p.Put(obj2)
+
+	if err := p.StopContext(context.Background()); err != nil {
+		t.Fatalf("Expected drain to complete once objects were returned, got: %v", err)
+	}
+	if got := destroyed.Load(); got != 2 {
+		t.Fatalf("Expected both objects to be destroyed, got: %d", got)
+	}
+}
+
 // This test subjects the pool to high concurrent load to verify its
 // stability and performance under stress. It:
 //
@@ -339,3 +749,291 @@ func TestPoolStressTest(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+// This test verifies that PrefillParallelism builds every object and that
+// the pool works normally afterwards, i.e. parallel prefill is just a faster
+// way of getting to the same fully-populated pool as sequential prefill.
+func TestPoolPrefillParallel(t *testing.T) {
+	t.Parallel()
+
+	var built atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 20,
+			NewFunc: func() (int, error) {
+				time.Sleep(10 * time.Millisecond)
+				return int(built.Add(1)), nil
+			},
+			PrefillParallelism: 5,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	if got := built.Load(); got != 20 {
+		t.Fatalf("Expected 20 objects built, got: %d", got)
+	}
+	if stats := p.Stats(); stats.Idle != 20 {
+		t.Fatalf("Expected 20 idle objects, got: %d", stats.Idle)
+	}
+
+	obj, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+	p.Put(obj)
+}
+
+// This test verifies that when one of several objects being built in
+// parallel fails, New unwinds cleanly: it waits for every in-flight
+// goroutine to finish, destroys every object any of them managed to build,
+// and returns the failure wrapped in ErrNew.
+func TestPoolPrefillParallelError(t *testing.T) {
+	t.Parallel()
+
+	var built, destroyed atomic.Int64
+
+	_, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 10,
+			NewFunc: func() (int, error) {
+				n := built.Add(1)
+				if n == 3 {
+					return 0, fmt.Errorf("object %d failed to build", n)
+				}
+				time.Sleep(10 * time.Millisecond)
+				return int(n), nil
+			},
+			DestroyFunc:        func(int) { destroyed.Add(1) },
+			PrefillParallelism: 4,
+		},
+	)
+	if err == nil {
+		t.Fatal("Expected error, got: nil")
+	}
+	if !errors.Is(err, simplepool.ErrNew) {
+		t.Fatalf("Expected ErrNew error, got: %v", err)
+	}
+
+	// Every object built before the failure aborted the rest should have
+	// been destroyed again; none should be leaked.
+	if got := destroyed.Load(); got != built.Load()-1 {
+		t.Fatalf("Expected all %d successfully built objects to be destroyed, got: %d", built.Load()-1, got)
+	}
+}
+
+// This test verifies that PrefillTimeout aborts a slow parallel prefill and
+// that New returns the timeout error wrapped in ErrNew, rather than hanging
+// until every object is built.
+func TestPoolPrefillParallelTimeout(t *testing.T) {
+	t.Parallel()
+
+	_, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 10,
+			NewFunc: func() (int, error) {
+				time.Sleep(time.Second)
+				return 0, nil
+			},
+			PrefillParallelism: 2,
+			PrefillTimeout:     50 * time.Millisecond,
+		},
+	)
+	if err == nil {
+		t.Fatal("Expected error, got: nil")
+	}
+	if !errors.Is(err, simplepool.ErrNew) {
+		t.Fatalf("Expected ErrNew error, got: %v", err)
+	}
+}
+
+// This test verifies that EventHook's callbacks fire for the lifecycle
+// events they document, and that Stats reflects a matching snapshot of the
+// pool's state and counters.
+func TestPoolEventHookAndStats(t *testing.T) {
+	t.Parallel()
+
+	var gets, puts, destroys, waits, stopStarts, stopDones atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count:   2,
+			NewFunc: func() (int, error) { return 0, nil },
+			EventHook: &simplepool.EventHook{
+				OnGet:       func() { gets.Add(1) },
+				OnPut:       func() { puts.Add(1) },
+				OnDestroy:   func() { destroys.Add(1) },
+				OnWait:      func(time.Duration) { waits.Add(1) },
+				OnStopStart: func() { stopStarts.Add(1) },
+				OnStopDone:  func() { stopDones.Add(1) },
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+
+	obj1, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	if stats := p.Stats(); stats.Capacity != 2 || stats.Idle != 1 || stats.Busy != 1 || stats.TotalGets != 1 {
+		t.Fatalf("Unexpected stats after one Get: %+v", stats)
+	}
+
+	// The second object is idle, so this Get shouldn't need to wait.
+	obj2, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	// Wait for an object from another goroutine, so OnWait fires.
+	done := make(chan struct{})
+	go func() {
+		obj, err := p.Get(context.Background())
+		if err == nil {
+			p.Put(obj)
+		}
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	p.Put(obj1)
+	<-done
+
+	p.Put(obj2)
+	p.Stop()
+
+	if got := gets.Load(); got != 3 {
+		t.Errorf("Expected OnGet to fire 3 times, got: %d", got)
+	}
+	if got := puts.Load(); got != 3 {
+		t.Errorf("Expected OnPut to fire 3 times, got: %d", got)
+	}
+	if got := destroys.Load(); got != 2 {
+		t.Errorf("Expected OnDestroy to fire 2 times, got: %d", got)
+	}
+	if got := waits.Load(); got != 1 {
+		t.Errorf("Expected OnWait to fire once, got: %d", got)
+	}
+	if got := stopStarts.Load(); got != 1 {
+		t.Errorf("Expected OnStopStart to fire once, got: %d", got)
+	}
+	if got := stopDones.Load(); got != 1 {
+		t.Errorf("Expected OnStopDone to fire once, got: %d", got)
+	}
+
+	if stats := p.Stats(); stats.TotalPuts != 3 || stats.TotalWaitNanos <= 0 {
+		t.Fatalf("Unexpected final stats: %+v", stats)
+	}
+}
+
+// This test verifies that Stats accounts for lost slots separately from busy
+// ones: a slot that's been destroyed but couldn't be replaced because
+// NewFunc failed is neither idle nor held by any caller, so it must show up
+// as Lost rather than inflating Busy.
+func TestPoolStatsLost(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count: 2,
+			NewFunc: func() (int, error) {
+				if calls.Add(1) == 3 {
+					return 0, fmt.Errorf("no more objects")
+				}
+				return 0, nil
+			},
+			ResetFunc: func(int) error {
+				return fmt.Errorf("reset always fails")
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	obj, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	// ResetFunc fails, and so does the replacement (the third NewFunc call),
+	// leaving this slot lost rather than idle or busy.
+	p.Put(obj)
+
+	if stats := p.Stats(); stats.Idle != 1 || stats.Busy != 0 || stats.Lost != 1 {
+		t.Fatalf("Expected 1 idle, 0 busy, 1 lost, got: %+v", stats)
+	}
+}
+
+// This test verifies TryGet's three outcomes: it returns an idle object
+// immediately, reports unavailability without blocking when the pool is
+// empty, and returns ErrStoppingOrStopped once the pool is stopping.
+func TestPoolTryGet(t *testing.T) {
+	t.Parallel()
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count:   1,
+			NewFunc: func() (int, error) { return 0, nil },
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+
+	obj, ok, err := p.TryGet()
+	if err != nil || !ok {
+		t.Fatalf("Expected an idle object, got: obj=%v ok=%v err=%v", obj, ok, err)
+	}
+
+	_, ok, err = p.TryGet()
+	if err != nil || ok {
+		t.Fatalf("Expected no object without blocking, got: ok=%v err=%v", ok, err)
+	}
+
+	p.Put(obj)
+	p.Stop()
+
+	_, ok, err = p.TryGet()
+	if !errors.Is(err, simplepool.ErrStoppingOrStopped) || ok {
+		t.Fatalf("Expected ErrStoppingOrStopped, got: ok=%v err=%v", ok, err)
+	}
+}
+
+// This test verifies that GetWithTimeout returns context.DeadlineExceeded
+// when no object becomes idle before the deadline, and otherwise behaves
+// like Get.
+func TestPoolGetWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	p, err := simplepool.New(
+		simplepool.Config[int]{
+			Count:   1,
+			NewFunc: func() (int, error) { return 0, nil },
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	defer p.Stop()
+
+	obj, err := p.GetWithTimeout(time.Second)
+	if err != nil {
+		t.Fatalf("Failed to get object: %v", err)
+	}
+
+	_, err = p.GetWithTimeout(50 * time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	p.Put(obj)
+}